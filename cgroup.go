@@ -0,0 +1,442 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Common cgroup mount point for v2
+	cgroupV2Mountpoint = "/sys/fs/cgroup"
+	// Typical cgroup mount point for v1 CPU controller
+	cgroupV1Mountpoint = "/sys/fs/cgroup/cpu,cpuacct"
+
+	// cgroup v2 filenames
+	cpuMaxV2Filename    = "cpu.max"
+	cpuWeightV2Filename = "cpu.weight" // While not strictly asked, good to know
+
+	// cgroup v1 filenames
+	cpuQuotaV1Filename  = "cpu.cfs_quota_us"
+	cpuPeriodV1Filename = "cpu.cfs_period_us"
+	cpuSharesV1Filename = "cpu.shares"
+
+	// procSelfCgroup and procSelfMountinfo let resolveCgroupPath find the
+	// cgroup this process actually lives in, rather than assuming it sits
+	// at the root of the hierarchy.
+	procSelfCgroup    = "/proc/self/cgroup"
+	procSelfMountinfo = "/proc/self/mountinfo"
+
+	// v2UnifiedController is the "controller" passed to resolveCgroupPath
+	// to resolve the unified v2 hierarchy, whose /proc/self/cgroup entry
+	// has an empty controller list (e.g. "0::/user.slice/user-0.slice").
+	v2UnifiedController = ""
+)
+
+// CgroupInfo holds the parsed cgroup resource information for the calling
+// process: CPU limits plus memory, pids, and block I/O usage.
+type CgroupInfo struct {
+	CgroupVersion          string `json:"cgroup_version"`
+	CPUMax                 string `json:"cpu_max"`              // For v2: cpu.max, For v1: calculated quota in microseconds
+	CPUPeriod              string `json:"cpu_period"`           // For v2: from cpu.max, For v1: cpu.cfs_period_us
+	CPUShares              string `json:"cpu_shares,omitempty"` // For v1: cpu.shares, For v2: n/a
+	CPUWeight              string `json:"cpu_weight,omitempty"` // For v2: cpu.weight, For v1: n/a
+	BurstableCPUPercentage string `json:"burstable_cpu_percentage"`
+
+	MemoryLimitBytes      string `json:"memory_limit_bytes"`       // memory.max (v2) or memory.limit_in_bytes (v1)
+	MemoryUsageBytes      string `json:"memory_usage_bytes"`       // memory.current (v2) or memory.usage_in_bytes (v1)
+	MemoryWorkingSetBytes string `json:"memory_working_set_bytes"` // usage minus inactive file cache, as kubelet/cAdvisor compute it
+
+	PidsLimit   string `json:"pids_limit"`   // pids.max
+	PidsCurrent string `json:"pids_current"` // pids.current
+
+	IOReadBytes  string `json:"io_read_bytes"`  // summed across devices: io.stat's rbytes (v2) or blkio.throttle.io_service_bytes's Read (v1)
+	IOWriteBytes string `json:"io_write_bytes"` // summed across devices: io.stat's wbytes (v2) or blkio.throttle.io_service_bytes's Write (v1)
+
+	Error string `json:"error,omitempty"`
+}
+
+// collectCgroupInfo gathers CPU, memory, pids, and I/O info for the given
+// cgroup version ("v1" or "v2"), merging the per-subsystem results into a
+// single CgroupInfo. Errors from individual subsystems are concatenated
+// rather than aborting the whole collection, so a failure reading e.g.
+// blkio stats doesn't hide an otherwise-successful CPU/memory readout.
+func collectCgroupInfo(version string) CgroupInfo {
+	var info CgroupInfo
+	var errs []string
+
+	switch version {
+	case "v2":
+		info = getCPUMaxInfoV2()
+	case "v1":
+		info = getCPUMaxInfoV1()
+	default:
+		info.CgroupVersion = "unknown"
+		info.Error = "Could not detect cgroup version (v1 or v2). Ensure /sys/fs/cgroup is correctly mounted and accessible."
+		return info
+	}
+	if info.Error != "" {
+		errs = append(errs, info.Error)
+		info.Error = ""
+	}
+
+	var mem, pids, io CgroupInfo
+	if version == "v2" {
+		mem, pids, io = getMemoryInfoV2(), getPidsInfoV2(), getIOInfoV2()
+	} else {
+		mem, pids, io = getMemoryInfoV1(), getPidsInfoV1(), getIOInfoV1()
+	}
+
+	info.MemoryLimitBytes = mem.MemoryLimitBytes
+	info.MemoryUsageBytes = mem.MemoryUsageBytes
+	info.MemoryWorkingSetBytes = mem.MemoryWorkingSetBytes
+	if mem.Error != "" {
+		errs = append(errs, mem.Error)
+	}
+
+	info.PidsLimit = pids.PidsLimit
+	info.PidsCurrent = pids.PidsCurrent
+	if pids.Error != "" {
+		errs = append(errs, pids.Error)
+	}
+
+	info.IOReadBytes = io.IOReadBytes
+	info.IOWriteBytes = io.IOWriteBytes
+	if io.Error != "" {
+		errs = append(errs, io.Error)
+	}
+
+	info.Error = strings.Join(errs, "; ")
+	return info
+}
+
+// CgroupResolveError reports why resolveCgroupPath could not determine the
+// effective cgroup path for a controller.
+type CgroupResolveError struct {
+	Controller string
+	Reason     string
+}
+
+func (e *CgroupResolveError) Error() string {
+	if e.Controller == v2UnifiedController {
+		return fmt.Sprintf("resolving cgroup v2 path: %s", e.Reason)
+	}
+	return fmt.Sprintf("resolving cgroup path for controller %q: %s", e.Controller, e.Reason)
+}
+
+// detectCgroupVersion checks which cgroup version is active for the current process
+func detectCgroupVersion() string {
+	// Check for cgroup v2 unified hierarchy
+	// If /sys/fs/cgroup/cgroup.controllers exists and is readable, it's likely v2
+	if _, err := os.Stat(filepath.Join(cgroupV2Mountpoint, "cgroup.controllers")); err == nil {
+		return "v2"
+	}
+	// Check for cgroup v1 cpuacct controller
+	if _, err := os.Stat(filepath.Join(cgroupV1Mountpoint, cpuQuotaV1Filename)); err == nil {
+		return "v1"
+	}
+
+	// Neither well-known mountpoint is present; fall back to reading
+	// /proc/self/cgroup directly, since some runtimes mount the
+	// hierarchies elsewhere.
+	content, err := ioutil.ReadFile(procSelfCgroup)
+	if err != nil {
+		return "unknown"
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		if fields[1] == "" {
+			return "v2"
+		}
+		for _, c := range strings.Split(fields[1], ",") {
+			if c == "cpu" || c == "cpuacct" {
+				return "v1"
+			}
+		}
+	}
+	return "unknown"
+}
+
+// resolveCgroupPath locates the actual cgroup path for the calling process
+// by combining /proc/self/cgroup with the matching mountpoint from
+// /proc/self/mountinfo, instead of assuming the process sits at the root of
+// a hierarchy mounted at the well-known location. Pass a v1 controller name
+// (e.g. "cpu" or "cpuacct") or v2UnifiedController ("") for the v2 unified
+// hierarchy. This makes the tool work correctly from inside nested cgroups,
+// such as systemd slices or Kubernetes pods.
+func resolveCgroupPath(controller string) (string, error) {
+	cgroupContent, err := ioutil.ReadFile(procSelfCgroup)
+	if err != nil {
+		return "", &CgroupResolveError{Controller: controller, Reason: fmt.Sprintf("reading %s: %v", procSelfCgroup, err)}
+	}
+
+	relPath, found := parseCgroupEntry(string(cgroupContent), controller)
+	if !found {
+		return "", &CgroupResolveError{Controller: controller, Reason: fmt.Sprintf("no matching entry in %s", procSelfCgroup)}
+	}
+
+	mountRoot, mountPoint, err := findCgroupMount(controller)
+	if err != nil {
+		return "", &CgroupResolveError{Controller: controller, Reason: err.Error()}
+	}
+
+	// relPath is rooted at the hierarchy's mount root (often "/"), not
+	// necessarily the filesystem root, so strip that prefix before joining
+	// onto the real mountpoint.
+	relPath = strings.TrimPrefix(relPath, mountRoot)
+	if relPath == "" || relPath == "/" {
+		return mountPoint, nil
+	}
+	return filepath.Join(mountPoint, relPath), nil
+}
+
+// parseCgroupEntry scans the contents of /proc/self/cgroup (one "hierarchy-
+// ID:controllers:path" line per hierarchy) for the entry matching
+// controller, returning its path and true, or ("", false) if none matches.
+// Pass v2UnifiedController ("") to match the v2 unified hierarchy's empty
+// controller list.
+func parseCgroupEntry(content, controller string) (path string, found bool) {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		controllers, path := fields[1], fields[2]
+
+		if controllers == "" {
+			if controller == v2UnifiedController {
+				return path, true
+			}
+			continue
+		}
+		for _, c := range strings.Split(controllers, ",") {
+			if c == controller {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
+
+// findCgroupMount scans /proc/self/mountinfo for the cgroup (v1) or cgroup2
+// (v2) mount backing the given controller, returning that mount's root
+// (field 4) and mount point (field 5).
+func findCgroupMount(controller string) (root string, mountPoint string, err error) {
+	content, err := ioutil.ReadFile(procSelfMountinfo)
+	if err != nil {
+		return "", "", fmt.Errorf("reading %s: %w", procSelfMountinfo, err)
+	}
+
+	root, mountPoint, err = parseMountInfoEntry(string(content), controller)
+	if err != nil {
+		return "", "", err
+	}
+	return root, mountPoint, nil
+}
+
+// parseMountInfoEntry scans the contents of /proc/self/mountinfo for the
+// cgroup (v1) or cgroup2 (v2) mount backing controller, returning that
+// mount's root (field 4) and mount point (field 5). Pass v2UnifiedController
+// ("") to match the first cgroup2 mount found.
+func parseMountInfoEntry(content, controller string) (root string, mountPoint string, err error) {
+	wantV2 := controller == v2UnifiedController
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		sepIdx := -1
+		for i, f := range fields {
+			if f == "-" {
+				sepIdx = i
+				break
+			}
+		}
+		if sepIdx < 0 || sepIdx+2 >= len(fields) || len(fields) < 5 {
+			continue
+		}
+
+		fsType := fields[sepIdx+1]
+		superOptions := fields[len(fields)-1]
+
+		if wantV2 {
+			if fsType != "cgroup2" {
+				continue
+			}
+			return fields[3], fields[4], nil
+		}
+
+		if fsType != "cgroup" {
+			continue
+		}
+		for _, opt := range strings.Split(superOptions, ",") {
+			if opt == controller {
+				return fields[3], fields[4], nil
+			}
+		}
+	}
+
+	return "", "", fmt.Errorf("no mountinfo entry found for controller %q", controller)
+}
+
+// resolveCgroupV1Dir resolves the v1 cgroup directory for the CPU
+// controllers, trying "cpu" and falling back to "cpuacct" since some hosts
+// mount them as separate hierarchies rather than the combined "cpu,cpuacct".
+func resolveCgroupV1Dir() (string, error) {
+	if dir, err := resolveCgroupPath("cpu"); err == nil {
+		return dir, nil
+	}
+	return resolveCgroupPath("cpuacct")
+}
+
+// cgroupDirOrFallback resolves the cgroup directory for controller (see
+// resolveCgroupPath), falling back to fallback, a well-known mountpoint
+// constant, when resolution fails.
+func cgroupDirOrFallback(controller, fallback string) string {
+	if dir, err := resolveCgroupPath(controller); err == nil {
+		return dir
+	}
+	return fallback
+}
+
+// cgroupV1DirOrFallback resolves the v1 CPU cgroup directory (see
+// resolveCgroupV1Dir), falling back to fallback when resolution fails.
+func cgroupV1DirOrFallback(fallback string) string {
+	if dir, err := resolveCgroupV1Dir(); err == nil {
+		return dir
+	}
+	return fallback
+}
+
+// getCPUMaxInfoV2 reads CPU limits from cgroup v2 files
+func getCPUMaxInfoV2() CgroupInfo {
+	info := CgroupInfo{CgroupVersion: "v2"}
+
+	cgroupDir := cgroupDirOrFallback(v2UnifiedController, cgroupV2Mountpoint)
+	cpuMaxPath := filepath.Join(cgroupDir, cpuMaxV2Filename)
+
+	content, err := ioutil.ReadFile(cpuMaxPath)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error reading %s: %v", cpuMaxPath, err)
+		return info
+	}
+
+	parts := strings.Fields(string(content))
+	if len(parts) < 2 {
+		info.Error = fmt.Sprintf("Error: Unexpected format in %s: %s", cpuMaxPath, string(content))
+		return info
+	}
+
+	maxValueStr := parts[0]
+	periodStr := parts[1]
+
+	info.CPUPeriod = fmt.Sprintf("%s microseconds", periodStr)
+
+	var maxValue int64
+	if maxValueStr == "max" {
+		info.CPUMax = "unlimited (max)"
+		info.BurstableCPUPercentage = "N/A (unlimited)"
+	} else {
+		maxValue, err = strconv.ParseInt(maxValueStr, 10, 64)
+		if err != nil {
+			info.Error = fmt.Sprintf("Error parsing max value from %s: %v", cpuMaxPath, err)
+			return info
+		}
+		info.CPUMax = fmt.Sprintf("%d microseconds", maxValue)
+
+		period, err := strconv.ParseInt(periodStr, 10, 64)
+		if err != nil {
+			info.Error = fmt.Sprintf("Error parsing period value from %s: %v", cpuMaxPath, err)
+			return info
+		}
+
+		if period > 0 {
+			burstablePercentage := float64(maxValue) / float64(period) * 100
+			info.BurstableCPUPercentage = fmt.Sprintf("%.2f%%", burstablePercentage)
+		} else {
+			info.BurstableCPUPercentage = "N/A (CPU period is zero)"
+		}
+	}
+
+	// Read cpu.weight as well for v2
+	cpuWeightPath := filepath.Join(cgroupDir, cpuWeightV2Filename)
+	weightContent, err := ioutil.ReadFile(cpuWeightPath)
+	if err != nil {
+		info.CPUWeight = fmt.Sprintf("Error reading %s: %v", cpuWeightPath, err)
+	} else {
+		info.CPUWeight = strings.TrimSpace(string(weightContent))
+	}
+
+	return info
+}
+
+// getCPUMaxInfoV1 reads CPU limits from cgroup v1 files
+func getCPUMaxInfoV1() CgroupInfo {
+	info := CgroupInfo{CgroupVersion: "v1"}
+
+	cgroupDir := cgroupV1DirOrFallback(cgroupV1Mountpoint)
+
+	quotaPath := filepath.Join(cgroupDir, cpuQuotaV1Filename)
+	periodPath := filepath.Join(cgroupDir, cpuPeriodV1Filename)
+	sharesPath := filepath.Join(cgroupDir, cpuSharesV1Filename)
+
+	quotaContent, err := ioutil.ReadFile(quotaPath)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error reading %s: %v", quotaPath, err)
+		return info
+	}
+	periodContent, err := ioutil.ReadFile(periodPath)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error reading %s: %v", periodPath, err)
+		return info
+	}
+	sharesContent, err := ioutil.ReadFile(sharesPath)
+	if err != nil {
+		info.CPUShares = fmt.Sprintf("Error reading %s: %v", sharesPath, err)
+	} else {
+		info.CPUShares = strings.TrimSpace(string(sharesContent))
+	}
+
+	quotaStr := strings.TrimSpace(string(quotaContent))
+	periodStr := strings.TrimSpace(string(periodContent))
+
+	period, err := strconv.ParseInt(periodStr, 10, 64)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error parsing period value from %s: %v", periodPath, err)
+		return info
+	}
+	info.CPUPeriod = fmt.Sprintf("%d microseconds", period)
+
+	quota, err := strconv.ParseInt(quotaStr, 10, 64)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error parsing quota value from %s: %v", quotaPath, err)
+		return info
+	}
+
+	if quota == -1 {
+		info.CPUMax = "unlimited (no quota)"
+		info.BurstableCPUPercentage = "N/A (unlimited)"
+	} else {
+		info.CPUMax = fmt.Sprintf("%d microseconds", quota)
+		if period > 0 {
+			burstablePercentage := float64(quota) / float64(period) * 100
+			info.BurstableCPUPercentage = fmt.Sprintf("%.2f%%", burstablePercentage)
+		} else {
+			info.BurstableCPUPercentage = "N/A (CPU period is zero)"
+		}
+	}
+
+	return info
+}
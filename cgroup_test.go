@@ -0,0 +1,110 @@
+package main
+
+import "testing"
+
+func TestParseCgroupEntry(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		controller string
+		wantPath   string
+		wantFound  bool
+	}{
+		{
+			name:       "v1 combined cpu,cpuacct controller",
+			content:    "5:cpu,cpuacct:/docker/abc123\n",
+			controller: "cpu",
+			wantPath:   "/docker/abc123",
+			wantFound:  true,
+		},
+		{
+			name:       "v1 separate cpuacct hierarchy",
+			content:    "10:cpuacct:/user.slice\n5:cpu:/docker/abc123\n",
+			controller: "cpuacct",
+			wantPath:   "/user.slice",
+			wantFound:  true,
+		},
+		{
+			name:       "v2 unified hierarchy",
+			content:    "0::/user.slice/user-1000.slice/session-2.scope\n",
+			controller: v2UnifiedController,
+			wantPath:   "/user.slice/user-1000.slice/session-2.scope",
+			wantFound:  true,
+		},
+		{
+			name:       "root cgroup",
+			content:    "0::/\n",
+			controller: v2UnifiedController,
+			wantPath:   "/",
+			wantFound:  true,
+		},
+		{
+			name:       "no matching entry",
+			content:    "5:memory:/docker/abc123\n",
+			controller: "cpu",
+			wantPath:   "",
+			wantFound:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, found := parseCgroupEntry(tt.content, tt.controller)
+			if path != tt.wantPath || found != tt.wantFound {
+				t.Errorf("parseCgroupEntry(%q, %q) = (%q, %v), want (%q, %v)",
+					tt.content, tt.controller, path, found, tt.wantPath, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestParseMountInfoEntry(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		controller     string
+		wantRoot       string
+		wantMountPoint string
+		wantErr        bool
+	}{
+		{
+			name:           "v1 cpu mount",
+			content:        "34 25 0:29 / /sys/fs/cgroup/cpu,cpuacct rw,nosuid shared:10 - cgroup cgroup rw,cpu,cpuacct\n",
+			controller:     "cpu",
+			wantRoot:       "/",
+			wantMountPoint: "/sys/fs/cgroup/cpu,cpuacct",
+		},
+		{
+			name:           "v2 cgroup2 mount",
+			content:        "30 24 0:27 / /sys/fs/cgroup rw,nosuid shared:9 - cgroup2 cgroup2 rw\n",
+			controller:     v2UnifiedController,
+			wantRoot:       "/",
+			wantMountPoint: "/sys/fs/cgroup",
+		},
+		{
+			name:       "no matching entry",
+			content:    "34 25 0:29 / /sys/fs/cgroup/memory rw,nosuid shared:10 - cgroup cgroup rw,memory\n",
+			controller: "cpu",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, mountPoint, err := parseMountInfoEntry(tt.content, tt.controller)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseMountInfoEntry(%q, %q) = (%q, %q, nil), want error", tt.content, tt.controller, root, mountPoint)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMountInfoEntry(%q, %q) returned unexpected error: %v", tt.content, tt.controller, err)
+			}
+			if root != tt.wantRoot || mountPoint != tt.wantMountPoint {
+				t.Errorf("parseMountInfoEntry(%q, %q) = (%q, %q), want (%q, %q)",
+					tt.content, tt.controller, root, mountPoint, tt.wantRoot, tt.wantMountPoint)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// RecommendedGOMAXPROCS computes the GOMAXPROCS value that best matches the
+// calling process's CPU quota, in the spirit of uber-go/automaxprocs:
+// ceil(quota/period), with a minimum of 1. Fractional CPU limits round up
+// (e.g. a 1.5 CPU quota recommends 2), since GOMAXPROCS must be a whole
+// number of OS threads. When no quota is set (v2 "max" or v1 "-1"), or the
+// cgroup files can't be read, it falls back to runtime.NumCPU().
+func RecommendedGOMAXPROCS() (int, error) {
+	quota, period, err := readCPUQuotaPeriod()
+	if err != nil {
+		return runtime.NumCPU(), err
+	}
+	if quota <= 0 || period <= 0 {
+		return runtime.NumCPU(), nil
+	}
+
+	cpus := int(math.Ceil(float64(quota) / float64(period)))
+	if cpus < 1 {
+		cpus = 1
+	}
+	return cpus, nil
+}
+
+// readCPUQuotaPeriod returns the raw CPU quota/period pair, in microseconds,
+// from whichever cgroup version is active. quota is -1 when unlimited.
+func readCPUQuotaPeriod() (quota int64, period int64, err error) {
+	switch detectCgroupVersion() {
+	case "v2":
+		return readCPUQuotaPeriodV2()
+	case "v1":
+		return readCPUQuotaPeriodV1()
+	default:
+		return 0, 0, fmt.Errorf("could not detect cgroup version (v1 or v2)")
+	}
+}
+
+// readCPUQuotaPeriodV2 parses cpu.max ("$MAX $PERIOD", or "max $PERIOD" when unlimited).
+func readCPUQuotaPeriodV2() (int64, int64, error) {
+	cgroupDir := cgroupDirOrFallback(v2UnifiedController, cgroupV2Mountpoint)
+	cpuMaxPath := filepath.Join(cgroupDir, cpuMaxV2Filename)
+
+	content, err := ioutil.ReadFile(cpuMaxPath)
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading %s: %w", cpuMaxPath, err)
+	}
+	parts := strings.Fields(string(content))
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("unexpected format in %s: %s", cpuMaxPath, string(content))
+	}
+
+	period, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parsing period from %s: %w", cpuMaxPath, err)
+	}
+	if parts[0] == "max" {
+		return -1, period, nil
+	}
+	quota, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parsing quota from %s: %w", cpuMaxPath, err)
+	}
+	return quota, period, nil
+}
+
+// readCPUQuotaPeriodV1 parses cpu.cfs_quota_us and cpu.cfs_period_us.
+func readCPUQuotaPeriodV1() (int64, int64, error) {
+	cgroupDir := cgroupV1DirOrFallback(cgroupV1Mountpoint)
+	quotaPath := filepath.Join(cgroupDir, cpuQuotaV1Filename)
+	periodPath := filepath.Join(cgroupDir, cpuPeriodV1Filename)
+
+	quotaContent, err := ioutil.ReadFile(quotaPath)
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading %s: %w", quotaPath, err)
+	}
+	periodContent, err := ioutil.ReadFile(periodPath)
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading %s: %w", periodPath, err)
+	}
+
+	quota, err := strconv.ParseInt(strings.TrimSpace(string(quotaContent)), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parsing %s: %w", quotaPath, err)
+	}
+	period, err := strconv.ParseInt(strings.TrimSpace(string(periodContent)), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parsing %s: %w", periodPath, err)
+	}
+	return quota, period, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var leadingNumberRe = regexp.MustCompile(`^-?\d+(\.\d+)?`)
+
+// parseLeadingFloat extracts the leading numeric token from strings like
+// "100000 microseconds" or "54.32%", returning ok=false for non-numeric
+// values such as "unlimited (max)" so callers can skip the metric instead
+// of reporting a bogus zero.
+func parseLeadingFloat(s string) (float64, bool) {
+	match := leadingNumberRe.FindString(strings.TrimSpace(s))
+	if match == "" {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(match, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// cgroupCollector implements prometheus.Collector. It re-reads cgroup files
+// on every Collect call rather than caching a snapshot, so /metrics always
+// reflects the container's live limits and usage.
+type cgroupCollector struct {
+	cpuQuota          *prometheus.Desc
+	cpuPeriod         *prometheus.Desc
+	cpuBurstableRatio *prometheus.Desc
+	memoryLimit       *prometheus.Desc
+	memoryWorkingSet  *prometheus.Desc
+	pidsMax           *prometheus.Desc
+	pidsCurrent       *prometheus.Desc
+}
+
+// newCgroupCollector builds a cgroupCollector with its metric descriptors.
+func newCgroupCollector() *cgroupCollector {
+	return &cgroupCollector{
+		cpuQuota: prometheus.NewDesc("container_cpu_quota_microseconds",
+			"CPU quota allotted per period, in microseconds.", []string{"cgroup_version"}, nil),
+		cpuPeriod: prometheus.NewDesc("container_cpu_period_microseconds",
+			"CPU scheduling period, in microseconds.", []string{"cgroup_version"}, nil),
+		cpuBurstableRatio: prometheus.NewDesc("container_cpu_burstable_ratio",
+			"Ratio of CPU quota to period, e.g. 1.5 for a 1500m CPU limit.", []string{"cgroup_version"}, nil),
+		memoryLimit: prometheus.NewDesc("container_memory_limit_bytes",
+			"Memory limit, in bytes.", []string{"cgroup_version"}, nil),
+		memoryWorkingSet: prometheus.NewDesc("container_memory_working_set_bytes",
+			"Memory working set (usage minus inactive file cache), in bytes.", []string{"cgroup_version"}, nil),
+		pidsMax: prometheus.NewDesc("container_pids_max",
+			"Maximum number of pids allowed in the cgroup.", []string{"cgroup_version"}, nil),
+		pidsCurrent: prometheus.NewDesc("container_pids_current",
+			"Current number of pids in the cgroup.", []string{"cgroup_version"}, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *cgroupCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.cpuQuota
+	ch <- c.cpuPeriod
+	ch <- c.cpuBurstableRatio
+	ch <- c.memoryLimit
+	ch <- c.memoryWorkingSet
+	ch <- c.pidsMax
+	ch <- c.pidsCurrent
+}
+
+// Collect implements prometheus.Collector, re-reading cgroup state on every scrape.
+func (c *cgroupCollector) Collect(ch chan<- prometheus.Metric) {
+	info := collectCgroupInfo(detectCgroupVersion())
+	version := info.CgroupVersion
+
+	if quota, ok := parseLeadingFloat(info.CPUMax); ok {
+		ch <- prometheus.MustNewConstMetric(c.cpuQuota, prometheus.GaugeValue, quota, version)
+	}
+	if period, ok := parseLeadingFloat(info.CPUPeriod); ok {
+		ch <- prometheus.MustNewConstMetric(c.cpuPeriod, prometheus.GaugeValue, period, version)
+	}
+	if percentage, ok := parseLeadingFloat(info.BurstableCPUPercentage); ok {
+		ch <- prometheus.MustNewConstMetric(c.cpuBurstableRatio, prometheus.GaugeValue, percentage/100, version)
+	}
+	if limit, ok := parseLeadingFloat(info.MemoryLimitBytes); ok {
+		ch <- prometheus.MustNewConstMetric(c.memoryLimit, prometheus.GaugeValue, limit, version)
+	}
+	if workingSet, ok := parseLeadingFloat(info.MemoryWorkingSetBytes); ok {
+		ch <- prometheus.MustNewConstMetric(c.memoryWorkingSet, prometheus.GaugeValue, workingSet, version)
+	}
+	if pidsMax, ok := parseLeadingFloat(info.PidsLimit); ok {
+		ch <- prometheus.MustNewConstMetric(c.pidsMax, prometheus.GaugeValue, pidsMax, version)
+	}
+	if pidsCurrent, ok := parseLeadingFloat(info.PidsCurrent); ok {
+		ch <- prometheus.MustNewConstMetric(c.pidsCurrent, prometheus.GaugeValue, pidsCurrent, version)
+	}
+}
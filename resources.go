@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// v1 fallback mountpoints for controllers other than CPU, mirroring
+	// cgroupV1Mountpoint, used when resolveCgroupPath can't find a more
+	// precise path.
+	cgroupV1MemoryMountpoint = "/sys/fs/cgroup/memory"
+	cgroupV1PidsMountpoint   = "/sys/fs/cgroup/pids"
+	cgroupV1BlkioMountpoint  = "/sys/fs/cgroup/blkio"
+
+	// memory.stat uses the same "key value" format on v1 and v2.
+	memoryStatFilename = "memory.stat"
+
+	memoryLimitV1Filename   = "memory.limit_in_bytes"
+	memoryUsageV1Filename   = "memory.usage_in_bytes"
+	memoryMaxV2Filename     = "memory.max"
+	memoryCurrentV2Filename = "memory.current"
+
+	// maxPracticalMemoryLimit is the threshold above which memory.limit_in_bytes
+	// is treated as "no limit set" rather than a real byte count; the kernel
+	// reports a page-rounded value near math.MaxInt64 in that case.
+	maxPracticalMemoryLimit = int64(1) << 62
+
+	// pids.max and pids.current use identical filenames on v1 and v2.
+	pidsMaxFilename     = "pids.max"
+	pidsCurrentFilename = "pids.current"
+
+	ioStatV2Filename          = "io.stat"
+	blkioServiceBytesFilename = "blkio.throttle.io_service_bytes"
+)
+
+// readStatField reads a "key value" formatted stat file (memory.stat on
+// both v1 and v2) and returns the integer value for key.
+func readStatField(path, key string) (int64, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != key {
+			continue
+		}
+		return strconv.ParseInt(fields[1], 10, 64)
+	}
+	return 0, fmt.Errorf("key %q not found", key)
+}
+
+// getMemoryInfoV2 reads memory limit, usage, and working set from cgroup v2 files.
+func getMemoryInfoV2() CgroupInfo {
+	info := CgroupInfo{CgroupVersion: "v2"}
+
+	cgroupDir := cgroupDirOrFallback(v2UnifiedController, cgroupV2Mountpoint)
+
+	limitPath := filepath.Join(cgroupDir, memoryMaxV2Filename)
+	limitContent, err := ioutil.ReadFile(limitPath)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error reading %s: %v", limitPath, err)
+		return info
+	}
+	if limitStr := strings.TrimSpace(string(limitContent)); limitStr == "max" {
+		info.MemoryLimitBytes = "unlimited (max)"
+	} else {
+		info.MemoryLimitBytes = limitStr
+	}
+
+	usagePath := filepath.Join(cgroupDir, memoryCurrentV2Filename)
+	usageContent, err := ioutil.ReadFile(usagePath)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error reading %s: %v", usagePath, err)
+		return info
+	}
+	usage, err := strconv.ParseInt(strings.TrimSpace(string(usageContent)), 10, 64)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error parsing %s: %v", usagePath, err)
+		return info
+	}
+	info.MemoryUsageBytes = strconv.FormatInt(usage, 10)
+
+	statPath := filepath.Join(cgroupDir, memoryStatFilename)
+	inactiveFile, err := readStatField(statPath, "inactive_file")
+	if err != nil {
+		info.Error = fmt.Sprintf("Error reading %s: %v", statPath, err)
+		return info
+	}
+	info.MemoryWorkingSetBytes = strconv.FormatInt(usage-inactiveFile, 10)
+
+	return info
+}
+
+// getMemoryInfoV1 reads memory limit, usage, and working set from cgroup v1 files.
+func getMemoryInfoV1() CgroupInfo {
+	info := CgroupInfo{CgroupVersion: "v1"}
+
+	cgroupDir := cgroupDirOrFallback("memory", cgroupV1MemoryMountpoint)
+
+	limitPath := filepath.Join(cgroupDir, memoryLimitV1Filename)
+	limitContent, err := ioutil.ReadFile(limitPath)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error reading %s: %v", limitPath, err)
+		return info
+	}
+	limit, err := strconv.ParseInt(strings.TrimSpace(string(limitContent)), 10, 64)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error parsing %s: %v", limitPath, err)
+		return info
+	}
+	if limit > maxPracticalMemoryLimit {
+		info.MemoryLimitBytes = "unlimited (no limit)"
+	} else {
+		info.MemoryLimitBytes = strconv.FormatInt(limit, 10)
+	}
+
+	usagePath := filepath.Join(cgroupDir, memoryUsageV1Filename)
+	usageContent, err := ioutil.ReadFile(usagePath)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error reading %s: %v", usagePath, err)
+		return info
+	}
+	usage, err := strconv.ParseInt(strings.TrimSpace(string(usageContent)), 10, 64)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error parsing %s: %v", usagePath, err)
+		return info
+	}
+	info.MemoryUsageBytes = strconv.FormatInt(usage, 10)
+
+	statPath := filepath.Join(cgroupDir, memoryStatFilename)
+	totalInactiveFile, err := readStatField(statPath, "total_inactive_file")
+	if err != nil {
+		info.Error = fmt.Sprintf("Error reading %s: %v", statPath, err)
+		return info
+	}
+	info.MemoryWorkingSetBytes = strconv.FormatInt(usage-totalInactiveFile, 10)
+
+	return info
+}
+
+// readPidsInfo reads pids.max/pids.current from cgroupDir; the filenames
+// are identical between v1 and v2.
+func readPidsInfo(info CgroupInfo, cgroupDir string) CgroupInfo {
+	maxPath := filepath.Join(cgroupDir, pidsMaxFilename)
+	maxContent, err := ioutil.ReadFile(maxPath)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error reading %s: %v", maxPath, err)
+		return info
+	}
+	if maxStr := strings.TrimSpace(string(maxContent)); maxStr == "max" {
+		info.PidsLimit = "unlimited (max)"
+	} else {
+		info.PidsLimit = maxStr
+	}
+
+	currentPath := filepath.Join(cgroupDir, pidsCurrentFilename)
+	currentContent, err := ioutil.ReadFile(currentPath)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error reading %s: %v", currentPath, err)
+		return info
+	}
+	info.PidsCurrent = strings.TrimSpace(string(currentContent))
+
+	return info
+}
+
+// getPidsInfoV2 reads the pids controller's limit and current count from cgroup v2.
+func getPidsInfoV2() CgroupInfo {
+	info := CgroupInfo{CgroupVersion: "v2"}
+	cgroupDir := cgroupDirOrFallback(v2UnifiedController, cgroupV2Mountpoint)
+	return readPidsInfo(info, cgroupDir)
+}
+
+// getPidsInfoV1 reads the pids controller's limit and current count from cgroup v1.
+func getPidsInfoV1() CgroupInfo {
+	info := CgroupInfo{CgroupVersion: "v1"}
+	cgroupDir := cgroupDirOrFallback("pids", cgroupV1PidsMountpoint)
+	return readPidsInfo(info, cgroupDir)
+}
+
+// getIOInfoV2 sums per-device read/write byte counts out of io.stat.
+func getIOInfoV2() CgroupInfo {
+	info := CgroupInfo{CgroupVersion: "v2"}
+	cgroupDir := cgroupDirOrFallback(v2UnifiedController, cgroupV2Mountpoint)
+
+	statPath := filepath.Join(cgroupDir, ioStatV2Filename)
+	content, err := ioutil.ReadFile(statPath)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error reading %s: %v", statPath, err)
+		return info
+	}
+
+	var readBytes, writeBytes int64
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		for _, field := range fields {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			value, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			switch kv[0] {
+			case "rbytes":
+				readBytes += value
+			case "wbytes":
+				writeBytes += value
+			}
+		}
+	}
+
+	info.IOReadBytes = strconv.FormatInt(readBytes, 10)
+	info.IOWriteBytes = strconv.FormatInt(writeBytes, 10)
+	return info
+}
+
+// getIOInfoV1 sums per-device read/write byte counts out of
+// blkio.throttle.io_service_bytes, skipping its trailing "Total N" line.
+func getIOInfoV1() CgroupInfo {
+	info := CgroupInfo{CgroupVersion: "v1"}
+	cgroupDir := cgroupDirOrFallback("blkio", cgroupV1BlkioMountpoint)
+
+	statPath := filepath.Join(cgroupDir, blkioServiceBytesFilename)
+	content, err := ioutil.ReadFile(statPath)
+	if err != nil {
+		info.Error = fmt.Sprintf("Error reading %s: %v", statPath, err)
+		return info
+	}
+
+	var readBytes, writeBytes int64
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		value, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[1] {
+		case "Read":
+			readBytes += value
+		case "Write":
+			writeBytes += value
+		}
+	}
+
+	info.IOReadBytes = strconv.FormatInt(readBytes, 10)
+	info.IOWriteBytes = strconv.FormatInt(writeBytes, 10)
+	return info
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSampleInterval = 2 * time.Second
+	minSampleInterval     = 1 * time.Second
+
+	cpuAcctUsageV1Filename = "cpuacct.usage" // v1: cumulative CPU time, nanoseconds
+	cpuStatFilename        = "cpu.stat"      // v1: nr_periods/nr_throttled/throttled_time(ns); v2: usage_usec/nr_periods/nr_throttled/throttled_usec
+)
+
+// cpuSample is one point-in-time reading of cumulative CPU usage and
+// throttling counters, as reported by the kernel.
+type cpuSample struct {
+	usageNanos     int64
+	nrThrottled    int64
+	throttledNanos int64
+	at             time.Time
+}
+
+// streamHandler serves Server-Sent Events with live CPU usage/throttling
+// samples, one goroutine per connected client, polling every
+// "interval_seconds" query-param seconds (default 2, minimum 1). The
+// request's context is canceled automatically when the client disconnects.
+func streamHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	interval := sampleIntervalFromRequest(r)
+	version := detectCgroupVersion()
+
+	prev, err := sampleCPU(version)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ctx := r.Context()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			curr, err := sampleCPU(version)
+			if err != nil {
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", err)
+				flusher.Flush()
+				continue
+			}
+
+			fmt.Fprintf(w, "data: %s\n\n", formatSampleEvent(prev, curr, version))
+			flusher.Flush()
+			prev = curr
+		}
+	}
+}
+
+// sampleIntervalFromRequest reads "interval_seconds" from the query string,
+// falling back to defaultSampleInterval and enforcing minSampleInterval.
+func sampleIntervalFromRequest(r *http.Request) time.Duration {
+	raw := r.URL.Query().Get("interval_seconds")
+	if raw == "" {
+		return defaultSampleInterval
+	}
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds < 1 {
+		return defaultSampleInterval
+	}
+	interval := time.Duration(seconds) * time.Second
+	if interval < minSampleInterval {
+		return minSampleInterval
+	}
+	return interval
+}
+
+// sampleCPU reads one cumulative CPU usage/throttling sample for the active cgroup version.
+func sampleCPU(version string) (cpuSample, error) {
+	switch version {
+	case "v2":
+		return sampleCPUV2()
+	case "v1":
+		return sampleCPUV1()
+	default:
+		return cpuSample{}, fmt.Errorf("could not detect cgroup version (v1 or v2)")
+	}
+}
+
+// sampleCPUV2 reads usage_usec, nr_throttled, and throttled_usec from cpu.stat.
+func sampleCPUV2() (cpuSample, error) {
+	cgroupDir := cgroupDirOrFallback(v2UnifiedController, cgroupV2Mountpoint)
+	statPath := filepath.Join(cgroupDir, cpuStatFilename)
+
+	usageUsec, err := readStatField(statPath, "usage_usec")
+	if err != nil {
+		return cpuSample{}, fmt.Errorf("reading %s: %w", statPath, err)
+	}
+	nrThrottled, err := readStatField(statPath, "nr_throttled")
+	if err != nil {
+		return cpuSample{}, fmt.Errorf("reading %s: %w", statPath, err)
+	}
+	throttledUsec, err := readStatField(statPath, "throttled_usec")
+	if err != nil {
+		return cpuSample{}, fmt.Errorf("reading %s: %w", statPath, err)
+	}
+
+	return cpuSample{
+		usageNanos:     usageUsec * 1000,
+		nrThrottled:    nrThrottled,
+		throttledNanos: throttledUsec * 1000,
+		at:             time.Now(),
+	}, nil
+}
+
+// sampleCPUV1 reads cpuacct.usage plus nr_throttled/throttled_time from cpu.stat.
+func sampleCPUV1() (cpuSample, error) {
+	// cpuacct.usage lives under the cpuacct controller, which some hosts
+	// mount separately from cpu (see resolveCgroupV1Dir), so resolve it on
+	// its own rather than reusing the cpu.stat directory below.
+	usageDir := cgroupDirOrFallback("cpuacct", cgroupV1Mountpoint)
+	usagePath := filepath.Join(usageDir, cpuAcctUsageV1Filename)
+	usageContent, err := ioutil.ReadFile(usagePath)
+	if err != nil {
+		return cpuSample{}, fmt.Errorf("reading %s: %w", usagePath, err)
+	}
+	usageNanos, err := strconv.ParseInt(strings.TrimSpace(string(usageContent)), 10, 64)
+	if err != nil {
+		return cpuSample{}, fmt.Errorf("parsing %s: %w", usagePath, err)
+	}
+
+	cgroupDir := cgroupV1DirOrFallback(cgroupV1Mountpoint)
+	statPath := filepath.Join(cgroupDir, cpuStatFilename)
+	nrThrottled, err := readStatField(statPath, "nr_throttled")
+	if err != nil {
+		return cpuSample{}, fmt.Errorf("reading %s: %w", statPath, err)
+	}
+	throttledNanos, err := readStatField(statPath, "throttled_time")
+	if err != nil {
+		return cpuSample{}, fmt.Errorf("reading %s: %w", statPath, err)
+	}
+
+	return cpuSample{
+		usageNanos:     usageNanos,
+		nrThrottled:    nrThrottled,
+		throttledNanos: throttledNanos,
+		at:             time.Now(),
+	}, nil
+}
+
+// formatSampleEvent computes the instantaneous CPU cores used and
+// percentage of quota consumed between two samples, and renders them plus
+// the cumulative throttling counters as a single-line JSON SSE payload.
+func formatSampleEvent(prev, curr cpuSample, version string) string {
+	elapsed := curr.at.Sub(prev.at).Seconds()
+	var coresUsed float64
+	if elapsed > 0 {
+		coresUsed = float64(curr.usageNanos-prev.usageNanos) / elapsed / 1e9
+	}
+
+	quotaPercentage := "N/A"
+	if quota, period, err := readCPUQuotaPeriod(); err == nil && quota > 0 && period > 0 {
+		if quotaCores := float64(quota) / float64(period); quotaCores > 0 {
+			quotaPercentage = fmt.Sprintf("%.2f%%", coresUsed/quotaCores*100)
+		}
+	}
+
+	return fmt.Sprintf(
+		"{\"cgroup_version\":%q,\"cores_used\":%.4f,\"quota_percentage\":%q,\"nr_throttled\":%d,\"throttled_usec\":%d}",
+		version, coresUsed, quotaPercentage, curr.nrThrottled, curr.throttledNanos/1000,
+	)
+}
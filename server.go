@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewServer registers this package's HTTP handlers onto mux and returns it,
+// so the package can be embedded in other Go programs rather than only run
+// as this package's own binary.
+func NewServer(mux *http.ServeMux) *http.ServeMux {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(newCgroupCollector())
+
+	mux.HandleFunc("/", handler)
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/gomaxprocs", gomaxprocsHandler)
+	mux.HandleFunc("/stream", streamHandler)
+	return mux
+}
+
+// gomaxprocsHandler serves the value RecommendedGOMAXPROCS computes from
+// the container's CPU quota.
+func gomaxprocsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	recommended, err := RecommendedGOMAXPROCS()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "Error: %v\n", err)
+		return
+	}
+	fmt.Fprintf(w, "%d\n", recommended)
+}
+
+// handler is the HTTP handler function for the root path "/". It returns
+// either the HTML dashboard or a JSON document, depending on the Accept
+// header or a "?format=json" query parameter.
+func handler(w http.ResponseWriter, r *http.Request) {
+	log.Printf("Received request from %s for %s", r.RemoteAddr, r.URL.Path)
+
+	info := collectCgroupInfo(detectCgroupVersion())
+
+	if wantsJSON(r) {
+		renderJSON(w, info)
+		return
+	}
+	renderHTML(w, info)
+}
+
+// wantsJSON reports whether the request asked for a JSON response, either
+// via "?format=json" or an Accept header naming application/json.
+func wantsJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return true
+	}
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+// renderJSON writes info as a JSON document, responding with a non-200
+// status when info.Error is set.
+func renderJSON(w http.ResponseWriter, info CgroupInfo) {
+	w.Header().Set("Content-Type", "application/json")
+	if info.Error != "" {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if err := json.NewEncoder(w).Encode(info); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
+
+// renderHTML writes info as the HTML dashboard page, responding with a
+// non-200 status when info.Error is set.
+func renderHTML(w http.ResponseWriter, info CgroupInfo) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if info.Error != "" {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	fmt.Fprintf(w, "<html><head><title>Container CPU Info</title>")
+	fmt.Fprintf(w, "<style>")
+	fmt.Fprintf(w, "body { font-family: Arial, sans-serif; margin: 20px; background-color: #f4f4f4; color: #333; }")
+	fmt.Fprintf(w, "div { background-color: #fff; padding: 20px; border-radius: 8px; box-shadow: 0 2px 4px rgba(0,0,0,0.1); max-width: 600px; margin: auto; }")
+	fmt.Fprintf(w, "h1 { color: #0056b3; text-align: center; }")
+	fmt.Fprintf(w, "p { font-size: 1.1em; line-height: 1.6; }")
+	fmt.Fprintf(w, "strong { color: #007bff; }")
+	fmt.Fprintf(w, ".error { color: red; font-weight: bold; }")
+	fmt.Fprintf(w, "</style>")
+	fmt.Fprintf(w, "</head><body>")
+	fmt.Fprintf(w, "<div>")
+	fmt.Fprintf(w, "<h1>Container CPU Information</h1>")
+
+	// A subsystem error (e.g. blkio not mounted) only means its own fields
+	// are empty; render every field that was successfully read instead of
+	// blanking the whole dashboard when any one subsystem fails.
+	if info.Error != "" {
+		fmt.Fprintf(w, "<p class=\"error\">Error: %s</p>", info.Error)
+	}
+	if info.CgroupVersion != "" {
+		fmt.Fprintf(w, "<p><strong>Cgroup Version:</strong> %s</p>", info.CgroupVersion)
+	}
+	if info.CPUMax != "" {
+		fmt.Fprintf(w, "<p><strong>CPU Max (burst) for container:</strong> %s</p>", info.CPUMax)
+	}
+	if info.CPUPeriod != "" {
+		fmt.Fprintf(w, "<p><strong>CPU Period for container:</strong> %s</p>", info.CPUPeriod)
+	}
+	if info.BurstableCPUPercentage != "" {
+		fmt.Fprintf(w, "<p><strong>Burstable CPU Percentage:</strong> %s</p>", info.BurstableCPUPercentage)
+	}
+	if info.CgroupVersion == "v1" && info.CPUShares != "" {
+		fmt.Fprintf(w, "<p><strong>CPU Shares (v1):</strong> %s</p>", info.CPUShares)
+	}
+	if info.CgroupVersion == "v2" && info.CPUWeight != "" {
+		fmt.Fprintf(w, "<p><strong>CPU Weight (v2):</strong> %s</p>", info.CPUWeight)
+	}
+	if info.MemoryLimitBytes != "" {
+		fmt.Fprintf(w, "<p><strong>Memory Limit:</strong> %s bytes</p>", info.MemoryLimitBytes)
+	}
+	if info.MemoryUsageBytes != "" {
+		fmt.Fprintf(w, "<p><strong>Memory Usage:</strong> %s bytes</p>", info.MemoryUsageBytes)
+	}
+	if info.MemoryWorkingSetBytes != "" {
+		fmt.Fprintf(w, "<p><strong>Memory Working Set:</strong> %s bytes</p>", info.MemoryWorkingSetBytes)
+	}
+	if info.PidsLimit != "" {
+		fmt.Fprintf(w, "<p><strong>Pids Limit:</strong> %s</p>", info.PidsLimit)
+	}
+	if info.PidsCurrent != "" {
+		fmt.Fprintf(w, "<p><strong>Pids Current:</strong> %s</p>", info.PidsCurrent)
+	}
+	if info.IOReadBytes != "" {
+		fmt.Fprintf(w, "<p><strong>IO Read Bytes:</strong> %s</p>", info.IOReadBytes)
+	}
+	if info.IOWriteBytes != "" {
+		fmt.Fprintf(w, "<p><strong>IO Write Bytes:</strong> %s</p>", info.IOWriteBytes)
+	}
+	fmt.Fprintf(w, "</div>")
+	fmt.Fprintf(w, "</body></html>")
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWantsJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		query  string
+		want   bool
+	}{
+		{name: "format=json query param", query: "format=json", want: true},
+		{name: "Accept application/json", accept: "application/json", want: true},
+		{name: "Accept with quality value", accept: "text/html,application/json;q=0.9", want: true},
+		{name: "Accept text/html only", accept: "text/html", want: false},
+		{name: "no Accept header or query param", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/"
+			if tt.query != "" {
+				url += "?" + tt.query
+			}
+			r := httptest.NewRequest(http.MethodGet, url, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			if got := wantsJSON(r); got != tt.want {
+				t.Errorf("wantsJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	info := CgroupInfo{
+		CgroupVersion: "v2",
+		CPUMax:        "100000",
+		CPUPeriod:     "100000",
+	}
+
+	w := httptest.NewRecorder()
+	renderJSON(w, info)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want %q", ct, "application/json")
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	for _, key := range []string{"cgroup_version", "cpu_max", "cpu_period"} {
+		if _, ok := body[key]; !ok {
+			t.Errorf("body missing snake_case key %q: %v", key, body)
+		}
+	}
+}
+
+func TestRenderJSONErrorStatus(t *testing.T) {
+	info := CgroupInfo{Error: "Error reading /sys/fs/cgroup/memory.stat: no such file"}
+
+	w := httptest.NewRecorder()
+	renderJSON(w, info)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+// TestRenderHTMLPartialFailure is a regression test: a single subsystem
+// error must not hide data that was successfully read from other
+// subsystems.
+func TestRenderHTMLPartialFailure(t *testing.T) {
+	info := CgroupInfo{
+		CgroupVersion: "v2",
+		CPUMax:        "100000",
+		CPUPeriod:     "100000",
+		Error:         "Error reading /sys/fs/cgroup/memory.stat: no such file",
+	}
+
+	w := httptest.NewRecorder()
+	renderHTML(w, info)
+
+	body := w.Body.String()
+	if !strings.Contains(body, "100000") {
+		t.Errorf("body missing successfully-read CPU data: %s", body)
+	}
+	if !strings.Contains(body, info.Error) {
+		t.Errorf("body missing error message: %s", body)
+	}
+}